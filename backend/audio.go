@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// audioSendTimeout bounds how long the chunker will block trying to hand a
+// full frame to the Transcribe pipeline before giving up. This is the
+// "bounded time budget" backpressure: the WebSocket read loop stalls for up
+// to this long under load instead of silently dropping audio.
+const audioSendTimeout = 2 * time.Second
+
+// AudioChunker accumulates incoming PCM into fixed-size frames and forwards
+// each completed frame to out, applying backpressure rather than dropping
+// when the downstream consumer falls behind. If a ring buffer is supplied,
+// any frame that never makes it to out (because backpressure timed out or
+// the session is tearing down) is retained there for replay on resume —
+// only audio that was *not* successfully forwarded is retained.
+type AudioChunker struct {
+	frameSize int
+	buf       []byte
+	out       chan<- []byte
+	ctx       context.Context
+	ring      *RingBuffer
+}
+
+func newAudioChunker(frameSize int, out chan<- []byte, ctx context.Context, ring *RingBuffer) *AudioChunker {
+	return &AudioChunker{
+		frameSize: frameSize,
+		out:       out,
+		ctx:       ctx,
+		ring:      ring,
+	}
+}
+
+// Write appends data to the pending buffer and emits every full frame it can
+// assemble from it.
+func (c *AudioChunker) Write(data []byte) {
+	c.buf = append(c.buf, data...)
+	for len(c.buf) >= c.frameSize {
+		frame := make([]byte, c.frameSize)
+		copy(frame, c.buf[:c.frameSize])
+		c.buf = c.buf[c.frameSize:]
+		c.emit(frame)
+	}
+}
+
+// Flush emits whatever partial frame remains, e.g. at end of stream.
+func (c *AudioChunker) Flush() {
+	if len(c.buf) == 0 {
+		return
+	}
+	frame := make([]byte, len(c.buf))
+	copy(frame, c.buf)
+	c.buf = nil
+	c.emit(frame)
+}
+
+// PendingBytes returns a copy of whatever hasn't yet been assembled into a
+// full frame. Unlike Flush, it doesn't attempt to send it anywhere — it's
+// for callers that are abandoning the stream (e.g. a dropped connection)
+// and want to know exactly what was never forwarded.
+func (c *AudioChunker) PendingBytes() []byte {
+	out := make([]byte, len(c.buf))
+	copy(out, c.buf)
+	return out
+}
+
+func (c *AudioChunker) emit(frame []byte) {
+	select {
+	case c.out <- frame:
+		recordFrameSent(len(frame))
+	case <-c.ctx.Done():
+		c.retain(frame)
+	case <-time.After(audioSendTimeout):
+		recordFrameDropped()
+		log.Println("Audio pipeline backpressure timeout exceeded, dropping frame")
+		c.retain(frame)
+	}
+}
+
+func (c *AudioChunker) retain(frame []byte) {
+	if c.ring != nil {
+		c.ring.Write(frame)
+	}
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/transcribe"
+	transcribetypes "github.com/aws/aws-sdk-go-v2/service/transcribe/types"
+)
+
+// JobService handles the batch/asynchronous transcription workflow, as an
+// alternative to the live streaming path in handleConnection.
+type JobService struct {
+	cfg              *Config
+	transcribeClient *transcribe.Client
+	s3Client         *s3.Client
+	uploader         *manager.Uploader
+	sink             TranscriptSink
+}
+
+func newJobService(cfg *Config) (*JobService, error) {
+	awsCfg, err := config.LoadDefaultConfig(context.Background(),
+		config.WithRegion(cfg.TranscribeRegion),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	s3Cfg, err := config.LoadDefaultConfig(context.Background(),
+		config.WithRegion(cfg.S3Region),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load S3 config: %w", err)
+	}
+	s3Client := s3.NewFromConfig(s3Cfg)
+
+	sink, err := newTranscriptSink(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up transcript sink: %w", err)
+	}
+
+	return &JobService{
+		cfg:              cfg,
+		transcribeClient: transcribe.NewFromConfig(awsCfg),
+		s3Client:         s3Client,
+		uploader:         manager.NewUploader(s3Client),
+		sink:             sink,
+	}, nil
+}
+
+// StartJobHandler accepts either a multipart audio upload ("audio" field) or
+// a JSON body pointing at an existing S3 object ({"s3_uri": "..."}), kicks
+// off a StartMedicalTranscriptionJob, and returns the job name so the client
+// can poll GetJobHandler.
+func (j *JobService) StartJobHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	specialty := getQueryOrDefault(r, "specialty", j.cfg.TranscribeSpecialty)
+	transcribeType := getQueryOrDefault(r, "type", j.cfg.TranscribeType)
+	languageCode := getQueryOrDefault(r, "language", j.cfg.TranscribeLanguage)
+
+	mediaURI, mediaFormat, err := j.resolveMediaURI(r)
+	if err != nil {
+		log.Printf("Failed to resolve media for job: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	jobName := fmt.Sprintf("medical-job-%d", time.Now().UnixNano())
+	outputKey := fmt.Sprintf("%s/jobs/%s", j.cfg.S3Prefix, jobName)
+
+	input := &transcribe.StartMedicalTranscriptionJobInput{
+		MedicalTranscriptionJobName: aws.String(jobName),
+		LanguageCode:                transcribetypes.LanguageCode(languageCode),
+		MediaFormat:                 transcribetypes.MediaFormat(mediaFormat),
+		Media: &transcribetypes.Media{
+			MediaFileUri: aws.String(mediaURI),
+		},
+		OutputBucketName: aws.String(j.cfg.S3Bucket),
+		OutputKey:        aws.String(outputKey + ".json"),
+		Specialty:        transcribetypes.Specialty(specialty),
+		Type:             transcribetypes.Type(transcribeType),
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	if _, err := j.transcribeClient.StartMedicalTranscriptionJob(ctx, input); err != nil {
+		log.Printf("Failed to start medical transcription job: %v", err)
+		http.Error(w, "failed to start transcription job", http.StatusInternalServerError)
+		return
+	}
+
+	go j.awaitCompletion(jobName, outputKey)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{
+		"job_name": jobName,
+		"status":   string(transcribetypes.TranscriptionJobStatusInProgress),
+	})
+}
+
+// GetJobHandler reports the live status of a previously submitted job by
+// asking AWS directly, so it works even if the server restarted.
+func (j *JobService) GetJobHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	jobName := strings.TrimPrefix(r.URL.Path, "/api/medical/jobs/")
+	if jobName == "" {
+		http.Error(w, "job id required", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	out, err := j.transcribeClient.GetMedicalTranscriptionJob(ctx, &transcribe.GetMedicalTranscriptionJobInput{
+		MedicalTranscriptionJobName: aws.String(jobName),
+	})
+	if err != nil {
+		log.Printf("Failed to get medical transcription job %s: %v", jobName, err)
+		http.Error(w, "job not found", http.StatusNotFound)
+		return
+	}
+
+	job := out.MedicalTranscriptionJob
+	resp := map[string]interface{}{
+		"job_name": jobName,
+		"status":   string(job.TranscriptionJobStatus),
+	}
+	if job.Transcript != nil && job.Transcript.TranscriptFileUri != nil {
+		resp["transcript_uri"] = aws.ToString(job.Transcript.TranscriptFileUri)
+	}
+	if job.FailureReason != nil {
+		resp["failure_reason"] = aws.ToString(job.FailureReason)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// resolveMediaURI either streams a multipart "audio" file up to S3 via
+// s3manager, or accepts a JSON body referencing an existing S3 object.
+func (j *JobService) resolveMediaURI(r *http.Request) (string, string, error) {
+	contentType := r.Header.Get("Content-Type")
+	if strings.HasPrefix(contentType, "multipart/form-data") {
+		if err := r.ParseMultipartForm(200 << 20); err != nil {
+			return "", "", fmt.Errorf("failed to parse upload: %w", err)
+		}
+		file, header, err := r.FormFile("audio")
+		if err != nil {
+			return "", "", fmt.Errorf("missing \"audio\" file: %w", err)
+		}
+		defer file.Close()
+
+		return j.uploadAudio(r.Context(), file, header)
+	}
+
+	var body struct {
+		S3URI string `json:"s3_uri"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.S3URI == "" {
+		return "", "", fmt.Errorf("request must be a multipart \"audio\" upload or JSON {\"s3_uri\": ...}")
+	}
+
+	return body.S3URI, mediaFormatFromExt(body.S3URI), nil
+}
+
+func (j *JobService) uploadAudio(ctx context.Context, file multipart.File, header *multipart.FileHeader) (string, string, error) {
+	key := fmt.Sprintf("%s/uploads/%d-%s", j.cfg.S3Prefix, time.Now().UnixNano(), filepath.Base(header.Filename))
+
+	_, err := j.uploader.Upload(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(j.cfg.S3Bucket),
+		Key:    aws.String(key),
+		Body:   file,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to upload audio to S3: %w", err)
+	}
+
+	uri := fmt.Sprintf("s3://%s/%s", j.cfg.S3Bucket, key)
+	return uri, mediaFormatFromExt(header.Filename), nil
+}
+
+func mediaFormatFromExt(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".wav":
+		return "wav"
+	case ".flac":
+		return "flac"
+	case ".ogg":
+		return "ogg"
+	case ".amr":
+		return "amr"
+	case ".webm":
+		return "webm"
+	default:
+		return "mp3"
+	}
+}
+
+// awaitCompletion polls the job until it is no longer QUEUED or IN_PROGRESS.
+// A failed job is only logged; otherwise the result and a flattened
+// transcript are mirrored into the configured TranscriptSink under S3Prefix.
+func (j *JobService) awaitCompletion(jobName, outputKey string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("Timed out waiting for medical transcription job %s", jobName)
+			return
+		case <-ticker.C:
+			out, err := j.transcribeClient.GetMedicalTranscriptionJob(ctx, &transcribe.GetMedicalTranscriptionJobInput{
+				MedicalTranscriptionJobName: aws.String(jobName),
+			})
+			if err != nil {
+				log.Printf("Failed to poll medical transcription job %s: %v", jobName, err)
+				return
+			}
+
+			status := out.MedicalTranscriptionJob.TranscriptionJobStatus
+			if status == transcribetypes.TranscriptionJobStatusInProgress || status == transcribetypes.TranscriptionJobStatusQueued {
+				continue
+			}
+
+			if status == transcribetypes.TranscriptionJobStatusFailed {
+				log.Printf("Medical transcription job %s failed: %s", jobName, aws.ToString(out.MedicalTranscriptionJob.FailureReason))
+				return
+			}
+
+			j.persistJobResult(ctx, jobName, outputKey)
+			return
+		}
+	}
+}
+
+// persistJobResult fetches the raw job output that AWS wrote to the job's
+// OutputBucketName (always a real S3 bucket — Transcribe has no concept of
+// our configured sink), then mirrors both that raw JSON and a flattened
+// transcript into the configured TranscriptSink so callers only ever need
+// to read results back from one place regardless of STORAGE_BACKEND.
+func (j *JobService) persistJobResult(ctx context.Context, jobName, outputKey string) {
+	resultObj, err := j.s3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(j.cfg.S3Bucket),
+		Key:    aws.String(outputKey + ".json"),
+	})
+	if err != nil {
+		log.Printf("Failed to fetch result for job %s: %v", jobName, err)
+		return
+	}
+	rawResult, err := io.ReadAll(resultObj.Body)
+	resultObj.Body.Close()
+	if err != nil {
+		log.Printf("Failed to read result for job %s: %v", jobName, err)
+		return
+	}
+
+	var result struct {
+		Results struct {
+			Transcripts []struct {
+				Transcript string `json:"transcript"`
+			} `json:"transcripts"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(rawResult, &result); err != nil {
+		log.Printf("Failed to decode result for job %s: %v", jobName, err)
+		return
+	}
+
+	if err := j.sink.PutTranscript(ctx, outputKey+".json", "application/json", bytes.NewReader(rawResult)); err != nil {
+		log.Printf("Failed to persist raw transcript for job %s: %v", jobName, err)
+		return
+	}
+
+	var sb strings.Builder
+	for _, t := range result.Results.Transcripts {
+		sb.WriteString(t.Transcript)
+	}
+
+	if err := j.sink.PutTranscript(ctx, outputKey+".txt", "text/plain", strings.NewReader(sb.String())); err != nil {
+		log.Printf("Failed to persist flattened transcript for job %s: %v", jobName, err)
+		return
+	}
+
+	log.Printf("Medical transcription job %s completed, transcript saved to %s.txt", jobName, outputKey)
+}
+
+func getQueryOrDefault(r *http.Request, key, defaultValue string) string {
+	if value := r.URL.Query().Get(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
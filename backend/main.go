@@ -14,7 +14,6 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/transcribestreaming"
 	"github.com/aws/aws-sdk-go-v2/service/transcribestreaming/types"
 	"github.com/gorilla/websocket"
@@ -39,25 +38,73 @@ type Config struct {
 	TranscribeSpecialty  string
 	TranscribeType       string
 	SampleRateHz         int32
+
+	// StorageBackend selects the TranscriptSink implementation: "s3"
+	// (default), "minio", or "local".
+	StorageBackend     string
+	S3Endpoint         string
+	S3PathStyle        bool
+	LocalTranscriptDir string
+
+	// AudioFrameSize is the size, in bytes, of each PCM frame the audio
+	// chunker assembles before forwarding it to Transcribe. 15KB matches
+	// the AWS SDK integration-test default.
+	AudioFrameSize int
+
+	// SessionResumeTTL is how long a disconnected session is kept around,
+	// ring buffer and all, waiting for a ?session=<id> reconnect before it
+	// is finalized and discarded.
+	SessionResumeTTL time.Duration
+	// RingBufferSeconds is how many seconds of PCM audio a session retains
+	// so a resumed connection can replay the tail that was never forwarded.
+	RingBufferSeconds int
+
+	// VocabularyName, VocabularyFilterName, and VocabularyFilterMethod are
+	// threaded into StartMedicalStreamTranscriptionInput when set, letting a
+	// client opt into a custom medical vocabulary or PHI redaction filter.
+	VocabularyName         string
+	VocabularyFilterName   string
+	VocabularyFilterMethod string
 }
 
 type WSMessage struct {
-	Type   string `json:"type"`
-	Text   string `json:"text,omitempty"`
-	Key    string `json:"key,omitempty"`
-	Action string `json:"action,omitempty"`
+	Type      string   `json:"type"`
+	Text      string   `json:"text,omitempty"`
+	Key       string   `json:"key,omitempty"`
+	Action    string   `json:"action,omitempty"`
+	Segment   *Segment `json:"segment,omitempty"`
+	SessionID string   `json:"session_id,omitempty"`
+
+	// Configure fields: set on a "control"/"configure" message to opt a
+	// session into a custom medical vocabulary or vocabulary filter.
+	VocabularyName         string `json:"vocabulary_name,omitempty"`
+	VocabularyFilterName   string `json:"vocabulary_filter_name,omitempty"`
+	VocabularyFilterMethod string `json:"vocabulary_filter_method,omitempty"`
 }
 
 type TranscriptionSession struct {
-	conn            *websocket.Conn
+	id               string
+	conn             *websocket.Conn
 	transcribeClient *transcribestreaming.Client
-	s3Client        *s3.Client
-	config          Config
-	transcripts     []string
-	lastTranscript  string // Track the last transcript to detect duplicates
-	mu              sync.Mutex
-	ctx             context.Context
-	cancel          context.CancelFunc
+	sink             TranscriptSink
+	config           Config
+	transcripts      []string
+	segments         []Segment
+	lastTranscript   string // Track the last transcript to detect duplicates
+	mu               sync.Mutex
+	ctx              context.Context
+	cancel           context.CancelFunc
+
+	// ring and disconnectedAt support session resumption: ring retains up
+	// to RingBufferSeconds worth of capacity, but in practice only ever
+	// holds PCM that was never forwarded (frames whose backpressure send
+	// timed out, queued frames drained on disconnect, and the trailing
+	// partial frame) —
+	// audio already handed off to AWS is not replayed. disconnectedAt
+	// (zero while connected) lets the SessionRegistry janitor know when
+	// the resume grace period has elapsed.
+	ring           *RingBuffer
+	disconnectedAt time.Time
 }
 
 func loadConfig() (*Config, error) {
@@ -69,6 +116,21 @@ func loadConfig() (*Config, error) {
 		sampleRate = 16000
 	}
 
+	audioFrameSize, err := strconv.Atoi(os.Getenv("AUDIO_FRAME_SIZE"))
+	if err != nil || audioFrameSize <= 0 {
+		audioFrameSize = 15 * 1024
+	}
+
+	sessionResumeTTL, err := time.ParseDuration(os.Getenv("SESSION_RESUME_TTL"))
+	if err != nil || sessionResumeTTL <= 0 {
+		sessionResumeTTL = 30 * time.Second
+	}
+
+	ringBufferSeconds, err := strconv.Atoi(os.Getenv("AUDIO_RING_BUFFER_SECONDS"))
+	if err != nil || ringBufferSeconds <= 0 {
+		ringBufferSeconds = 5
+	}
+
 	return &Config{
 		AWSAccessKeyID:       os.Getenv("AWS_ACCESS_KEY_ID"),
 		AWSSecretAccessKey:   os.Getenv("AWS_SECRET_ACCESS_KEY"),
@@ -80,6 +142,16 @@ func loadConfig() (*Config, error) {
 		TranscribeSpecialty:  getEnvOrDefault("TRANSCRIBE_SPECIALTY", "PRIMARYCARE"),
 		TranscribeType:       getEnvOrDefault("TRANSCRIBE_TYPE", "DICTATION"),
 		SampleRateHz:         int32(sampleRate),
+
+		StorageBackend:     getEnvOrDefault("STORAGE_BACKEND", "s3"),
+		S3Endpoint:         os.Getenv("S3_ENDPOINT"),
+		S3PathStyle:        parseBoolEnv("S3_PATH_STYLE", false),
+		LocalTranscriptDir: os.Getenv("LOCAL_TRANSCRIPT_DIR"),
+
+		AudioFrameSize: audioFrameSize,
+
+		SessionResumeTTL:  sessionResumeTTL,
+		RingBufferSeconds: ringBufferSeconds,
 	}, nil
 }
 
@@ -96,11 +168,28 @@ func main() {
 		log.Fatal("Failed to load config:", err)
 	}
 
+	registry := newSessionRegistry(cfg.SessionResumeTTL)
+
 	http.HandleFunc("/health", healthHandler)
+	http.HandleFunc("/metrics", metricsHandler)
 	http.HandleFunc("/ws/medical/direct", func(w http.ResponseWriter, r *http.Request) {
-		wsHandler(w, r, cfg)
+		wsHandler(w, r, cfg, registry)
 	})
 
+	jobService, err := newJobService(cfg)
+	if err != nil {
+		log.Fatal("Failed to set up job service:", err)
+	}
+	http.HandleFunc("/api/medical/jobs", jobService.StartJobHandler)
+	http.HandleFunc("/api/medical/jobs/", jobService.GetJobHandler)
+
+	vocabService, err := newVocabularyService(cfg)
+	if err != nil {
+		log.Fatal("Failed to set up vocabulary service:", err)
+	}
+	http.HandleFunc("/api/medical/vocabularies", vocabService.VocabulariesHandler)
+	http.HandleFunc("/api/medical/vocabulary-filters", vocabService.VocabularyFiltersHandler)
+
 	port := getEnvOrDefault("PORT", "8000")
 	log.Printf("Server starting on port %s", port)
 	if err := http.ListenAndServe(":"+port, nil); err != nil {
@@ -113,7 +202,7 @@ func healthHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("OK"))
 }
 
-func wsHandler(w http.ResponseWriter, r *http.Request, cfg *Config) {
+func wsHandler(w http.ResponseWriter, r *http.Request, cfg *Config, registry *SessionRegistry) {
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Printf("WebSocket upgrade failed: %v", err)
@@ -126,67 +215,112 @@ func wsHandler(w http.ResponseWriter, r *http.Request, cfg *Config) {
 	if specialty == "" {
 		specialty = cfg.TranscribeSpecialty
 	}
-	
+
 	transcribeType := r.URL.Query().Get("type")
 	if transcribeType == "" {
 		transcribeType = cfg.TranscribeType
 	}
 
-	// Create AWS clients
-	awsCfg, err := config.LoadDefaultConfig(context.Background(),
-		config.WithRegion(cfg.TranscribeRegion),
-	)
-	if err != nil {
-		log.Printf("Failed to load AWS config: %v", err)
-		return
+	resumed := false
+	var session *TranscriptionSession
+	if requestedID := r.URL.Query().Get("session"); requestedID != "" {
+		if existing, ok := registry.get(requestedID); ok {
+			session = existing
+			resumed = true
+			log.Printf("Resuming session %s", session.id)
+		} else {
+			log.Printf("Session %s not found or expired, starting a new one", requestedID)
+		}
 	}
 
-	transcribeClient := transcribestreaming.NewFromConfig(awsCfg)
-	
-	// S3 client might be in a different region
-	s3Cfg, err := config.LoadDefaultConfig(context.Background(),
-		config.WithRegion(cfg.S3Region),
+	if session == nil {
+		sink, err := newTranscriptSink(cfg)
+		if err != nil {
+			log.Printf("Failed to set up transcript sink: %v", err)
+			return
+		}
+
+		sessionConfig := *cfg
+		sessionConfig.TranscribeSpecialty = specialty
+		sessionConfig.TranscribeType = transcribeType
+		if vocab := r.URL.Query().Get("vocabulary_name"); vocab != "" {
+			sessionConfig.VocabularyName = vocab
+		}
+		if filter := r.URL.Query().Get("vocabulary_filter_name"); filter != "" {
+			sessionConfig.VocabularyFilterName = filter
+		}
+		if method := r.URL.Query().Get("vocabulary_filter_method"); method != "" {
+			sessionConfig.VocabularyFilterMethod = method
+		}
+
+		session = &TranscriptionSession{
+			id:          generateSessionID(),
+			sink:        sink,
+			config:      sessionConfig,
+			transcripts: []string{},
+			ring:        newRingBuffer(ringBufferCapacity(cfg, cfg.RingBufferSeconds)),
+		}
+		registry.put(session)
+	}
+
+	// (Re)create the AWS clients and per-connection context. A resumed
+	// session's previous stream and context are long gone, so this always
+	// opens a fresh StartMedicalStreamTranscription below.
+	awsCfg, err := config.LoadDefaultConfig(context.Background(),
+		config.WithRegion(session.config.TranscribeRegion),
 	)
 	if err != nil {
-		log.Printf("Failed to load S3 config: %v", err)
+		log.Printf("Failed to load AWS config: %v", err)
 		return
 	}
-	s3Client := s3.NewFromConfig(s3Cfg)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Create config copy with overrides
-	sessionConfig := *cfg
-	sessionConfig.TranscribeSpecialty = specialty
-	sessionConfig.TranscribeType = transcribeType
-
-	session := &TranscriptionSession{
-		conn:             conn,
-		transcribeClient: transcribeClient,
-		s3Client:         s3Client,
-		config:           sessionConfig,
-		ctx:              ctx,
-		cancel:           cancel,
-		transcripts:      []string{},
-		lastTranscript:   "",
+	session.mu.Lock()
+	session.conn = conn
+	session.transcribeClient = transcribestreaming.NewFromConfig(awsCfg)
+	session.ctx = ctx
+	session.cancel = cancel
+	session.disconnectedAt = time.Time{}
+	session.mu.Unlock()
+
+	if resumed {
+		session.sendMessage(WSMessage{Type: "resume", SessionID: session.id})
+	} else {
+		session.sendMessage(WSMessage{Type: "session_id", SessionID: session.id})
 	}
 
-	session.handleConnection()
+	session.handleConnection(registry)
 }
 
-func (s *TranscriptionSession) handleConnection() {
-	log.Println("WebSocket connection established")
+func (s *TranscriptionSession) handleConnection(registry *SessionRegistry) {
+	log.Printf("WebSocket connection established for session %s", s.id)
+
+	// Give the client a brief window to send a "control"/"configure"
+	// message (e.g. to pick a vocabulary) before audio starts flowing. If
+	// audio arrives first instead, it's captured here and replayed once the
+	// stream is up rather than discarded.
+	pendingAudio := s.awaitInitialConfig()
 
 	// Start the transcription stream
 	streamInput := &transcribestreaming.StartMedicalStreamTranscriptionInput{
-		LanguageCode:         types.LanguageCode(s.config.TranscribeLanguage),
-		MediaSampleRateHertz: aws.Int32(s.config.SampleRateHz),
-		MediaEncoding:        types.MediaEncodingPcm,
-		Specialty:            types.Specialty(s.config.TranscribeSpecialty),
-		Type:                 types.Type(s.config.TranscribeType),
+		LanguageCode:                types.LanguageCode(s.config.TranscribeLanguage),
+		MediaSampleRateHertz:        aws.Int32(s.config.SampleRateHz),
+		MediaEncoding:               types.MediaEncodingPcm,
+		Specialty:                   types.Specialty(s.config.TranscribeSpecialty),
+		Type:                        types.Type(s.config.TranscribeType),
 		EnableChannelIdentification: true,
-		NumberOfChannels:     aws.Int32(2),
+		NumberOfChannels:            aws.Int32(2),
+	}
+	if s.config.VocabularyName != "" {
+		streamInput.VocabularyName = aws.String(s.config.VocabularyName)
+	}
+	if s.config.VocabularyFilterName != "" {
+		streamInput.VocabularyFilterName = aws.String(s.config.VocabularyFilterName)
+	}
+	if s.config.VocabularyFilterMethod != "" {
+		streamInput.VocabularyFilterMethod = types.VocabularyFilterMethod(s.config.VocabularyFilterMethod)
 	}
 
 	stream, err := s.transcribeClient.StartMedicalStreamTranscription(s.ctx, streamInput)
@@ -200,12 +334,29 @@ func (s *TranscriptionSession) handleConnection() {
 	audioChan := make(chan []byte, 100)
 	doneChan := make(chan struct{})
 
+	chunker := newAudioChunker(s.config.AudioFrameSize, audioChan, s.ctx, s.ring)
+
+	// Replay whatever tail of audio a previous connection never got to
+	// forward before it dropped. The ring only ever holds bytes that never
+	// reached sendAudioToTranscribe (frames kept by AudioChunker.retain,
+	// plus the queued frames and partial frame drained on disconnect), so
+	// this never re-sends audio that already made it to Transcribe.
+	if replay := s.ring.Take(); len(replay) > 0 {
+		log.Printf("Replaying %d buffered bytes for session %s", len(replay), s.id)
+		chunker.Write(replay)
+	}
+	if len(pendingAudio) > 0 {
+		chunker.Write(pendingAudio)
+	}
+
 	// Start goroutine to handle transcription events
 	go s.handleTranscriptionEvents(stream, doneChan)
 
 	// Start goroutine to send audio to Transcribe
 	go s.sendAudioToTranscribe(stream, audioChan, doneChan)
 
+	stopped := false
+
 	// Read messages from WebSocket
 	for {
 		messageType, message, err := s.conn.ReadMessage()
@@ -220,30 +371,54 @@ func (s *TranscriptionSession) handleConnection() {
 			if err := json.Unmarshal(message, &msg); err == nil {
 				if msg.Type == "control" && msg.Action == "stop" {
 					log.Println("Received stop command")
+					stopped = true
 					break
 				}
 			}
 		} else if messageType == websocket.BinaryMessage {
-			// Forward audio data
-			select {
-			case audioChan <- message:
-			case <-s.ctx.Done():
-				break
-			default:
-				log.Println("Audio buffer full, dropping frame")
-			}
+			// Accumulate into frames, blocking (bounded) under backpressure
+			// instead of dropping outright. Frames that do get forwarded
+			// are never written to the ring buffer (see AudioChunker) —
+			// only audio that isn't successfully handed off lives there.
+			chunker.Write(message)
+		}
+	}
+
+	if stopped {
+		// Finalizing deliberately: forward whatever's left, then tear down.
+		chunker.Flush()
+		close(audioChan)
+		s.cancel()
+		<-doneChan
+
+		registry.remove(s.id)
+		if len(s.transcripts) > 0 {
+			s.persistTranscript()
 		}
+		return
 	}
 
-	// Clean up
+	// The WebSocket dropped rather than being stopped deliberately. Close
+	// audioChan and drain whatever full frames are still queued in it
+	// before cancelling the context: sendAudioToTranscribe's select could
+	// otherwise pick s.ctx.Done() over a buffered frame and abandon it
+	// without ever reaching the ring. Only the frames that neither this
+	// drain nor sendAudioToTranscribe got to forward are retained — this
+	// is still a bounded tail, not the full N seconds the ring is sized
+	// for, since audio already handed off to AWS isn't replayed.
 	close(audioChan)
+	for frame := range audioChan {
+		s.ring.Write(frame)
+	}
+	s.ring.Write(chunker.PendingBytes())
 	s.cancel()
 	<-doneChan
 
-	// Save transcription to S3
-	if len(s.transcripts) > 0 {
-		s.saveToS3()
-	}
+	s.mu.Lock()
+	s.conn = nil
+	s.disconnectedAt = time.Now()
+	s.mu.Unlock()
+	log.Printf("Session %s disconnected, holding for resume", s.id)
 }
 
 func (s *TranscriptionSession) handleTranscriptionEvents(stream *transcribestreaming.StartMedicalStreamTranscriptionOutput, done chan struct{}) {
@@ -252,7 +427,7 @@ func (s *TranscriptionSession) handleTranscriptionEvents(stream *transcribestrea
 	eventStream := stream.GetStream()
 	defer eventStream.Close()
 
-		for {
+	for {
 		select {
 		case <-s.ctx.Done():
 			return
@@ -284,7 +459,9 @@ func (s *TranscriptionSession) processTranscriptEvent(event types.MedicalTranscr
 			if !result.IsPartial {
 				// Final transcript
 				log.Printf("Received final transcript: %s", text)
-				
+
+				segment := buildSegment(result, alternative)
+
 				s.mu.Lock()
 				// Check if this transcript is different from the last one or contains the last one
 				isDuplicate := false
@@ -320,8 +497,19 @@ func (s *TranscriptionSession) processTranscriptEvent(event types.MedicalTranscr
 				}
 				
 				if !isDuplicate {
+					// The dedup logic above may have trimmed text down to
+					// only the newly-extended part; keep the structured
+					// segment's text (and word list) in sync with that same
+					// trimmed text so the flat and structured artifacts
+					// never disagree.
+					if segment.Text != text {
+						segment.Words = wordsAfterPrefix(segment.Words, segment.Text, text)
+						segment.Text = text
+					}
+
 					s.transcripts = append(s.transcripts, text)
 					s.lastTranscript = text
+					s.segments = append(s.segments, segment)
 					log.Printf("Added transcript. Total transcripts stored: %d", len(s.transcripts))
 				}
 				s.mu.Unlock()
@@ -332,6 +520,11 @@ func (s *TranscriptionSession) processTranscriptEvent(event types.MedicalTranscr
 						Text: text,
 					}
 					s.sendMessage(msg)
+
+					s.sendMessage(WSMessage{
+						Type:    "final_structured",
+						Segment: &segment,
+					})
 				}
 			} else {
 				// Partial transcript
@@ -345,6 +538,45 @@ func (s *TranscriptionSession) processTranscriptEvent(event types.MedicalTranscr
 	}
 }
 
+// buildSegment converts a single streaming Result/MedicalAlternative pair
+// into the stable Segment schema persisted in transcription_*.json.
+func buildSegment(result types.MedicalResult, alternative types.MedicalAlternative) Segment {
+	segment := Segment{
+		ChannelID: aws.ToString(result.ChannelId),
+		StartTime: result.StartTime,
+		EndTime:   result.EndTime,
+		Text:      aws.ToString(alternative.Transcript),
+	}
+
+	for _, item := range alternative.Items {
+		word := Word{
+			Text:      aws.ToString(item.Content),
+			StartTime: item.StartTime,
+			EndTime:   item.EndTime,
+			Type:      string(item.Type),
+		}
+		if item.Confidence != nil {
+			word.Confidence = aws.ToFloat64(item.Confidence)
+		}
+		if segment.Speaker == "" && item.Speaker != nil {
+			segment.Speaker = aws.ToString(item.Speaker)
+		}
+		segment.Words = append(segment.Words, word)
+	}
+
+	for _, entity := range alternative.Entities {
+		segment.Entities = append(segment.Entities, Entity{
+			Category:   aws.ToString(entity.Category),
+			Content:    aws.ToString(entity.Content),
+			StartTime:  entity.StartTime,
+			EndTime:    entity.EndTime,
+			Confidence: aws.ToFloat64(entity.Confidence),
+		})
+	}
+
+	return segment
+}
+
 func (s *TranscriptionSession) sendAudioToTranscribe(stream *transcribestreaming.StartMedicalStreamTranscriptionOutput, audioChan <-chan []byte, done <-chan struct{}) {
 	eventStream := stream.GetStream()
 
@@ -380,44 +612,42 @@ func (s *TranscriptionSession) sendAudioToTranscribe(stream *transcribestreaming
 	}
 }
 
-func (s *TranscriptionSession) saveToS3() {
+func (s *TranscriptionSession) persistTranscript() {
 	s.mu.Lock()
 	log.Printf("Saving transcription with %d segments", len(s.transcripts))
 	for i, transcript := range s.transcripts {
 		log.Printf("Transcript segment %d: %s", i, transcript)
 	}
 	fullText := strings.Join(s.transcripts, " ")
+	doc := TranscriptDocument{Segments: append([]Segment{}, s.segments...)}
 	s.mu.Unlock()
 
 	if fullText == "" {
 		log.Println("No transcription to save")
 		return
 	}
-	
+
 	log.Printf("Full transcription to save: %s", fullText)
 
 	// Generate filename with timestamp
 	timestamp := time.Now().Format("2006-01-02_15-04-05")
-	key := fmt.Sprintf("%s/transcription_%s.txt", s.config.S3Prefix, timestamp)
+	base := fmt.Sprintf("%s/transcription_%s", s.config.S3Prefix, timestamp)
+	key := base + ".txt"
 
-	// Upload to S3 with a fresh context since the session context may be canceled
+	// Persist with a fresh context since the session context may be canceled
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
-	
-	_, err := s.s3Client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket:      aws.String(s.config.S3Bucket),
-		Key:         aws.String(key),
-		Body:        strings.NewReader(fullText),
-		ContentType: aws.String("text/plain"),
-	})
 
+	err := s.sink.PutTranscript(ctx, key, "text/plain", strings.NewReader(fullText))
 	if err != nil {
-		log.Printf("Failed to upload to S3: %v", err)
+		log.Printf("Failed to save transcript: %v", err)
 		s.sendError("Failed to save transcription")
 		return
 	}
 
-	log.Printf("Transcription saved to S3: %s", key)
+	s.persistStructuredArtifacts(ctx, base, doc)
+
+	log.Printf("Transcription saved: %s", key)
 	msg := WSMessage{
 		Type: "saved",
 		Key:  key,
@@ -425,10 +655,33 @@ func (s *TranscriptionSession) saveToS3() {
 	s.sendMessage(msg)
 }
 
+// persistStructuredArtifacts writes the structured JSON transcript and its
+// WebVTT/SRT renderings alongside the flat .txt file. Failures here are
+// logged but don't fail the save, since the flat transcript already landed.
+func (s *TranscriptionSession) persistStructuredArtifacts(ctx context.Context, base string, doc TranscriptDocument) {
+	jsonBody, err := doc.toJSON()
+	if err != nil {
+		log.Printf("Failed to marshal structured transcript: %v", err)
+		return
+	}
+	if err := s.sink.PutTranscript(ctx, base+".json", "application/json", strings.NewReader(string(jsonBody))); err != nil {
+		log.Printf("Failed to save structured transcript: %v", err)
+	}
+	if err := s.sink.PutTranscript(ctx, base+".vtt", "text/vtt", strings.NewReader(doc.toWebVTT())); err != nil {
+		log.Printf("Failed to save WebVTT transcript: %v", err)
+	}
+	if err := s.sink.PutTranscript(ctx, base+".srt", "application/x-subrip", strings.NewReader(doc.toSRT())); err != nil {
+		log.Printf("Failed to save SRT transcript: %v", err)
+	}
+}
+
 func (s *TranscriptionSession) sendMessage(msg WSMessage) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.conn == nil {
+		return
+	}
 	if err := s.conn.WriteJSON(msg); err != nil {
 		log.Printf("Error sending message: %v", err)
 	}
@@ -440,4 +693,41 @@ func (s *TranscriptionSession) sendError(errorMsg string) {
 		Text: errorMsg,
 	}
 	s.sendMessage(msg)
-}
\ No newline at end of file
+}
+
+// awaitInitialConfig waits briefly for a "control"/"configure" message
+// before the transcription stream starts. If the client instead starts
+// sending audio right away, that frame is returned so the caller can
+// forward it once the stream is up instead of dropping it.
+func (s *TranscriptionSession) awaitInitialConfig() []byte {
+	s.conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	messageType, message, err := s.conn.ReadMessage()
+	s.conn.SetReadDeadline(time.Time{})
+	if err != nil {
+		return nil
+	}
+
+	if messageType == websocket.TextMessage {
+		var msg WSMessage
+		if err := json.Unmarshal(message, &msg); err == nil && msg.Type == "control" && msg.Action == "configure" {
+			s.applyConfigureMessage(msg)
+		}
+		return nil
+	}
+
+	return message
+}
+
+func (s *TranscriptionSession) applyConfigureMessage(msg WSMessage) {
+	if msg.VocabularyName != "" {
+		s.config.VocabularyName = msg.VocabularyName
+	}
+	if msg.VocabularyFilterName != "" {
+		s.config.VocabularyFilterName = msg.VocabularyFilterName
+	}
+	if msg.VocabularyFilterMethod != "" {
+		s.config.VocabularyFilterMethod = msg.VocabularyFilterMethod
+	}
+	log.Printf("Session %s configured: vocabulary=%q filter=%q filter_method=%q",
+		s.id, s.config.VocabularyName, s.config.VocabularyFilterName, s.config.VocabularyFilterMethod)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+)
+
+// Audio pipeline counters, exposed on /metrics in Prometheus text format.
+var (
+	metricFramesSent    uint64
+	metricBytesSent     uint64
+	metricFramesDropped uint64
+)
+
+func recordFrameSent(frameBytes int) {
+	atomic.AddUint64(&metricFramesSent, 1)
+	atomic.AddUint64(&metricBytesSent, uint64(frameBytes))
+}
+
+func recordFrameDropped() {
+	atomic.AddUint64(&metricFramesDropped, 1)
+}
+
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	fmt.Fprintf(w, "# HELP transcribe_medical_audio_frames_sent_total Audio frames forwarded to Transcribe.\n")
+	fmt.Fprintf(w, "# TYPE transcribe_medical_audio_frames_sent_total counter\n")
+	fmt.Fprintf(w, "transcribe_medical_audio_frames_sent_total %d\n", atomic.LoadUint64(&metricFramesSent))
+
+	fmt.Fprintf(w, "# HELP transcribe_medical_audio_bytes_sent_total Audio bytes forwarded to Transcribe.\n")
+	fmt.Fprintf(w, "# TYPE transcribe_medical_audio_bytes_sent_total counter\n")
+	fmt.Fprintf(w, "transcribe_medical_audio_bytes_sent_total %d\n", atomic.LoadUint64(&metricBytesSent))
+
+	fmt.Fprintf(w, "# HELP transcribe_medical_audio_frames_dropped_total Audio frames dropped due to backpressure timeout.\n")
+	fmt.Fprintf(w, "# TYPE transcribe_medical_audio_frames_dropped_total counter\n")
+	fmt.Fprintf(w, "transcribe_medical_audio_frames_dropped_total %d\n", atomic.LoadUint64(&metricFramesDropped))
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SessionRegistry tracks live and recently-disconnected TranscriptionSessions
+// by ID, so a client whose WebSocket drops can reconnect with ?session=<id>
+// and continue the same encounter instead of starting over.
+type SessionRegistry struct {
+	mu       sync.Mutex
+	sessions map[string]*TranscriptionSession
+	ttl      time.Duration
+}
+
+func newSessionRegistry(ttl time.Duration) *SessionRegistry {
+	r := &SessionRegistry{
+		sessions: make(map[string]*TranscriptionSession),
+		ttl:      ttl,
+	}
+	go r.janitor()
+	return r
+}
+
+func (r *SessionRegistry) put(s *TranscriptionSession) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions[s.id] = s
+}
+
+// get looks up a session by ID and, if found, immediately marks it as
+// reattached (clears disconnectedAt) while still holding r.mu. That closes
+// the window where the janitor's own r.mu-then-s.mu locking order could
+// otherwise observe the session as still-disconnected and reap it out from
+// under a concurrent resume.
+func (r *SessionRegistry) get(id string) (*TranscriptionSession, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.sessions[id]
+	if ok {
+		s.mu.Lock()
+		s.disconnectedAt = time.Time{}
+		s.mu.Unlock()
+	}
+	return s, ok
+}
+
+func (r *SessionRegistry) remove(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sessions, id)
+}
+
+// janitor reaps sessions that have been disconnected for longer than the
+// resume grace period, persisting whatever transcript they accumulated
+// before discarding them.
+func (r *SessionRegistry) janitor() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		var expired []*TranscriptionSession
+
+		r.mu.Lock()
+		for id, s := range r.sessions {
+			s.mu.Lock()
+			disconnected := s.conn == nil && !s.disconnectedAt.IsZero() && time.Since(s.disconnectedAt) > r.ttl
+			s.mu.Unlock()
+			if disconnected {
+				expired = append(expired, s)
+				delete(r.sessions, id)
+			}
+		}
+		r.mu.Unlock()
+
+		for _, s := range expired {
+			log.Printf("Session %s exceeded resume grace period, finalizing", s.id)
+			// persistTranscript takes s.mu itself and already no-ops on an
+			// empty transcript, so let it do the check instead of racing on
+			// s.transcripts here.
+			s.persistTranscript()
+		}
+	}
+}
+
+var sessionIDCounter uint64
+
+// generateSessionID produces a unique, reconnect-friendly session ID.
+func generateSessionID() string {
+	n := atomic.AddUint64(&sessionIDCounter, 1)
+	return fmt.Sprintf("session-%d-%d", time.Now().UnixNano(), n)
+}
+
+// RingBuffer retains up to capacity bytes of the most recently written PCM,
+// so a resumed session can replay the tail of audio the server never got to
+// forward before the previous connection dropped.
+type RingBuffer struct {
+	mu       sync.Mutex
+	data     []byte
+	capacity int
+}
+
+func newRingBuffer(capacity int) *RingBuffer {
+	return &RingBuffer{capacity: capacity}
+}
+
+func (b *RingBuffer) Write(p []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.data = append(b.data, p...)
+	if len(b.data) > b.capacity {
+		b.data = b.data[len(b.data)-b.capacity:]
+	}
+}
+
+// Take returns a copy of the buffered bytes, oldest first, and clears the
+// buffer. It's destructive on purpose: once handed to a caller for replay,
+// those bytes must not be handed out again on a later resume.
+func (b *RingBuffer) Take() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	out := b.data
+	b.data = nil
+	return out
+}
+
+// ringBufferCapacity computes the byte capacity needed to hold seconds of
+// 16-bit PCM audio at the configured sample rate and channel count.
+func ringBufferCapacity(cfg *Config, seconds int) int {
+	const bytesPerSample = 2
+	const channels = 2
+	return int(cfg.SampleRateHz) * bytesPerSample * channels * seconds
+}
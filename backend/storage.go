@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// TranscriptSink abstracts where finished transcripts (and any other
+// artifacts, such as structured JSON or VTT renderings) get written to.
+// This lets the same transcription pipeline run against real AWS S3,
+// an S3-compatible endpoint such as MinIO, or the local filesystem.
+type TranscriptSink interface {
+	PutTranscript(ctx context.Context, key, contentType string, body io.Reader) error
+}
+
+// newTranscriptSink builds the sink selected by STORAGE_BACKEND
+// ("s3", "minio", or "local"; defaults to "s3").
+func newTranscriptSink(cfg *Config) (TranscriptSink, error) {
+	switch cfg.StorageBackend {
+	case "minio":
+		return newMinIOSink(cfg)
+	case "local":
+		return newLocalFSSink(cfg)
+	case "s3", "":
+		return newS3Sink(cfg)
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_BACKEND %q", cfg.StorageBackend)
+	}
+}
+
+// S3Sink writes transcripts to AWS S3 using the ambient AWS config/credential
+// chain, same as the rest of the app.
+type S3Sink struct {
+	client *s3.Client
+	bucket string
+}
+
+func newS3Sink(cfg *Config) (*S3Sink, error) {
+	s3Cfg, err := config.LoadDefaultConfig(context.Background(),
+		config.WithRegion(cfg.S3Region),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load S3 config: %w", err)
+	}
+
+	return &S3Sink{
+		client: s3.NewFromConfig(s3Cfg),
+		bucket: cfg.S3Bucket,
+	}, nil
+}
+
+func (sink *S3Sink) PutTranscript(ctx context.Context, key, contentType string, body io.Reader) error {
+	_, err := sink.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(sink.bucket),
+		Key:         aws.String(key),
+		Body:        body,
+		ContentType: aws.String(contentType),
+	})
+	return err
+}
+
+// newMinIOSink builds an S3-compatible sink pointed at a custom endpoint
+// (MinIO, LocalStack, etc.) using static credentials, with path-style
+// addressing when S3_PATH_STYLE is true (it is off by default), so it works
+// without any AWS account at all.
+func newMinIOSink(cfg *Config) (*S3Sink, error) {
+	if cfg.S3Endpoint == "" {
+		return nil, fmt.Errorf("STORAGE_BACKEND=minio requires S3_ENDPOINT")
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(context.Background(),
+		config.WithRegion(cfg.S3Region),
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
+			cfg.AWSAccessKeyID, cfg.AWSSecretAccessKey, "",
+		)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load MinIO config: %w", err)
+	}
+
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		o.BaseEndpoint = aws.String(cfg.S3Endpoint)
+		o.UsePathStyle = cfg.S3PathStyle
+	})
+
+	return &S3Sink{
+		client: client,
+		bucket: cfg.S3Bucket,
+	}, nil
+}
+
+// LocalFSSink writes transcripts under a directory on disk, for offline
+// development without any object storage at all.
+type LocalFSSink struct {
+	dir string
+}
+
+func newLocalFSSink(cfg *Config) (*LocalFSSink, error) {
+	if cfg.LocalTranscriptDir == "" {
+		return nil, fmt.Errorf("STORAGE_BACKEND=local requires LOCAL_TRANSCRIPT_DIR")
+	}
+	if err := os.MkdirAll(cfg.LocalTranscriptDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create local transcript dir: %w", err)
+	}
+
+	return &LocalFSSink{dir: cfg.LocalTranscriptDir}, nil
+}
+
+func (sink *LocalFSSink) PutTranscript(ctx context.Context, key, contentType string, body io.Reader) error {
+	path := filepath.Join(sink.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", key, err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, body)
+	return err
+}
+
+func parseBoolEnv(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return defaultValue
+	}
+	return parsed
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Word is a single word-level (or punctuation) item from a transcript
+// alternative, with its timing and confidence.
+type Word struct {
+	Text       string  `json:"text"`
+	StartTime  float64 `json:"start_time"`
+	EndTime    float64 `json:"end_time"`
+	Confidence float64 `json:"confidence,omitempty"`
+	Type       string  `json:"type"` // "pronunciation" or "punctuation"
+}
+
+// Entity is a PHI/medication/anatomy (etc.) entity surfaced by the medical
+// transcript API for a given result.
+type Entity struct {
+	Category   string  `json:"category"`
+	Content    string  `json:"content"`
+	StartTime  float64 `json:"start_time"`
+	EndTime    float64 `json:"end_time"`
+	Confidence float64 `json:"confidence,omitempty"`
+}
+
+// Segment is one diarized, time-aligned result from the medical transcript
+// stream: a channel/speaker's utterance with its constituent words and any
+// entities detected within it.
+type Segment struct {
+	ChannelID string   `json:"channel_id,omitempty"`
+	Speaker   string   `json:"speaker,omitempty"`
+	StartTime float64  `json:"start_time"`
+	EndTime   float64  `json:"end_time"`
+	Text      string   `json:"text"`
+	Words     []Word   `json:"words,omitempty"`
+	Entities  []Entity `json:"entities,omitempty"`
+}
+
+// TranscriptDocument is the stable schema persisted as transcription_*.json
+// alongside the flat transcription_*.txt.
+type TranscriptDocument struct {
+	Segments []Segment `json:"segments"`
+}
+
+func (d TranscriptDocument) toJSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// toWebVTT renders the segments as a WebVTT file, one cue per word, keyed
+// off that word's own start/end time rather than the enclosing segment's.
+func (d TranscriptDocument) toWebVTT() string {
+	var sb strings.Builder
+	sb.WriteString("WEBVTT\n\n")
+	sb.WriteString(renderCues(d.Segments, formatVTTTimestamp))
+	return sb.String()
+}
+
+// toSRT renders the segments as a SubRip (.srt) file, one cue per word.
+func (d TranscriptDocument) toSRT() string {
+	return renderCues(d.Segments, formatSRTTimestamp)
+}
+
+// renderCues emits one cue per pronunciation word, using formatTS for its
+// timestamps, falling back to a single segment-level cue when a segment
+// has no per-word timing (e.g. a dedup pass that couldn't align one).
+func renderCues(segments []Segment, formatTS func(float64) string) string {
+	var sb strings.Builder
+	n := 1
+	for _, seg := range segments {
+		label := cueLabel(seg)
+
+		words := wordCues(seg)
+		if len(words) == 0 {
+			sb.WriteString(fmt.Sprintf("%d\n", n))
+			sb.WriteString(fmt.Sprintf("%s --> %s\n", formatTS(seg.StartTime), formatTS(seg.EndTime)))
+			sb.WriteString(label)
+			sb.WriteString(seg.Text)
+			sb.WriteString("\n\n")
+			n++
+			continue
+		}
+
+		for _, w := range words {
+			sb.WriteString(fmt.Sprintf("%d\n", n))
+			sb.WriteString(fmt.Sprintf("%s --> %s\n", formatTS(w.StartTime), formatTS(w.EndTime)))
+			sb.WriteString(label)
+			sb.WriteString(w.Text)
+			sb.WriteString("\n\n")
+			n++
+		}
+	}
+	return sb.String()
+}
+
+// wordCues returns the pronunciation words (punctuation has no useful
+// timing of its own) a segment can be cued on.
+func wordCues(seg Segment) []Word {
+	var words []Word
+	for _, w := range seg.Words {
+		if w.Type == "punctuation" {
+			continue
+		}
+		words = append(words, w)
+	}
+	return words
+}
+
+// wordsAfterPrefix returns the tail of words whose combined text lines up
+// with trimmedText, given that trimmedText is what's left of originalText
+// after a duplicate-detecting prefix was stripped from it. It's a
+// best-effort alignment: AWS's own spacing/punctuation rules make an exact
+// reconstruction impossible, so this returns the words from the first word
+// boundary at or after where trimmedText begins (nil if there is none).
+func wordsAfterPrefix(words []Word, originalText, trimmedText string) []Word {
+	idx := strings.Index(originalText, trimmedText)
+	if idx <= 0 {
+		return words
+	}
+
+	pos := 0
+	for i, w := range words {
+		if pos >= idx {
+			return words[i:]
+		}
+		pos += len(w.Text)
+		if w.Type != "punctuation" {
+			pos++ // account for the space the API inserts between pronunciations
+		}
+	}
+	return nil
+}
+
+func cueLabel(seg Segment) string {
+	if seg.Speaker != "" {
+		return fmt.Sprintf("[%s] ", seg.Speaker)
+	}
+	if seg.ChannelID != "" {
+		return fmt.Sprintf("[%s] ", seg.ChannelID)
+	}
+	return ""
+}
+
+func formatVTTTimestamp(seconds float64) string {
+	return formatTimestamp(seconds, ".")
+}
+
+func formatSRTTimestamp(seconds float64) string {
+	return formatTimestamp(seconds, ",")
+}
+
+func formatTimestamp(seconds float64, fractionSep string) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+	totalMillis := int64(seconds*1000 + 0.5)
+	hours := totalMillis / 3600000
+	totalMillis %= 3600000
+	minutes := totalMillis / 60000
+	totalMillis %= 60000
+	secs := totalMillis / 1000
+	millis := totalMillis % 1000
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", hours, minutes, secs, fractionSep, millis)
+}
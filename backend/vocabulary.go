@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/transcribe"
+	transcribetypes "github.com/aws/aws-sdk-go-v2/service/transcribe/types"
+)
+
+// VocabularyService wraps the non-streaming transcribe.Client calls needed
+// to manage custom medical vocabularies and vocabulary filters, so clinics
+// can maintain their own terminology/PHI-redaction lists without a
+// redeploy.
+type VocabularyService struct {
+	client *transcribe.Client
+}
+
+func newVocabularyService(cfg *Config) (*VocabularyService, error) {
+	awsCfg, err := config.LoadDefaultConfig(context.Background(),
+		config.WithRegion(cfg.TranscribeRegion),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	return &VocabularyService{client: transcribe.NewFromConfig(awsCfg)}, nil
+}
+
+// VocabulariesHandler serves POST (create), GET (list), and DELETE (remove)
+// on /api/medical/vocabularies.
+func (v *VocabularyService) VocabulariesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	switch r.Method {
+	case http.MethodPost:
+		var body struct {
+			VocabularyName    string   `json:"vocabulary_name"`
+			LanguageCode      string   `json:"language_code"`
+			Phrases           []string `json:"phrases,omitempty"`
+			VocabularyFileURI string   `json:"vocabulary_file_uri,omitempty"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.VocabularyName == "" || body.LanguageCode == "" {
+			http.Error(w, "vocabulary_name and language_code are required", http.StatusBadRequest)
+			return
+		}
+		if len(body.Phrases) == 0 && body.VocabularyFileURI == "" {
+			http.Error(w, "either phrases or vocabulary_file_uri is required", http.StatusBadRequest)
+			return
+		}
+
+		input := &transcribe.CreateMedicalVocabularyInput{
+			VocabularyName: aws.String(body.VocabularyName),
+			LanguageCode:   transcribetypes.LanguageCode(body.LanguageCode),
+		}
+		if body.VocabularyFileURI != "" {
+			input.VocabularyFileUri = aws.String(body.VocabularyFileURI)
+		}
+		if len(body.Phrases) > 0 {
+			input.Phrases = body.Phrases
+		}
+
+		if _, err := v.client.CreateMedicalVocabulary(ctx, input); err != nil {
+			log.Printf("Failed to create medical vocabulary %s: %v", body.VocabularyName, err)
+			http.Error(w, "failed to create vocabulary", http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]string{"vocabulary_name": body.VocabularyName})
+
+	case http.MethodGet:
+		out, err := v.client.ListMedicalVocabularies(ctx, &transcribe.ListMedicalVocabulariesInput{})
+		if err != nil {
+			log.Printf("Failed to list medical vocabularies: %v", err)
+			http.Error(w, "failed to list vocabularies", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(out.Vocabularies)
+
+	case http.MethodDelete:
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			http.Error(w, "name query parameter is required", http.StatusBadRequest)
+			return
+		}
+		if _, err := v.client.DeleteMedicalVocabulary(ctx, &transcribe.DeleteMedicalVocabularyInput{
+			VocabularyName: aws.String(name),
+		}); err != nil {
+			log.Printf("Failed to delete medical vocabulary %s: %v", name, err)
+			http.Error(w, "failed to delete vocabulary", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// VocabularyFiltersHandler serves POST (create), GET (list), and DELETE
+// (remove) on /api/medical/vocabulary-filters.
+func (v *VocabularyService) VocabularyFiltersHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	switch r.Method {
+	case http.MethodPost:
+		var body struct {
+			VocabularyFilterName    string   `json:"vocabulary_filter_name"`
+			LanguageCode            string   `json:"language_code"`
+			Words                   []string `json:"words,omitempty"`
+			VocabularyFilterFileURI string   `json:"vocabulary_filter_file_uri,omitempty"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.VocabularyFilterName == "" || body.LanguageCode == "" {
+			http.Error(w, "vocabulary_filter_name and language_code are required", http.StatusBadRequest)
+			return
+		}
+		if len(body.Words) == 0 && body.VocabularyFilterFileURI == "" {
+			http.Error(w, "either words or vocabulary_filter_file_uri is required", http.StatusBadRequest)
+			return
+		}
+
+		input := &transcribe.CreateMedicalVocabularyFilterInput{
+			VocabularyFilterName: aws.String(body.VocabularyFilterName),
+			LanguageCode:         transcribetypes.LanguageCode(body.LanguageCode),
+		}
+		if body.VocabularyFilterFileURI != "" {
+			input.VocabularyFilterFileUri = aws.String(body.VocabularyFilterFileURI)
+		}
+		if len(body.Words) > 0 {
+			input.Words = body.Words
+		}
+
+		if _, err := v.client.CreateMedicalVocabularyFilter(ctx, input); err != nil {
+			log.Printf("Failed to create medical vocabulary filter %s: %v", body.VocabularyFilterName, err)
+			http.Error(w, "failed to create vocabulary filter", http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]string{"vocabulary_filter_name": body.VocabularyFilterName})
+
+	case http.MethodGet:
+		out, err := v.client.ListMedicalVocabularyFilters(ctx, &transcribe.ListMedicalVocabularyFiltersInput{})
+		if err != nil {
+			log.Printf("Failed to list medical vocabulary filters: %v", err)
+			http.Error(w, "failed to list vocabulary filters", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(out.VocabularyFilters)
+
+	case http.MethodDelete:
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			http.Error(w, "name query parameter is required", http.StatusBadRequest)
+			return
+		}
+		if _, err := v.client.DeleteMedicalVocabularyFilter(ctx, &transcribe.DeleteMedicalVocabularyFilterInput{
+			VocabularyFilterName: aws.String(name),
+		}); err != nil {
+			log.Printf("Failed to delete medical vocabulary filter %s: %v", name, err)
+			http.Error(w, "failed to delete vocabulary filter", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}